@@ -0,0 +1,156 @@
+package sparql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Federation is a SPARQL client that fans a SELECT query out across
+// multiple independent endpoints and merges the results. It
+// complements SPARQL 1.1's SERVICE keyword for cases where the
+// federation must be driven from the client, e.g. across endpoints
+// that don't trust each other enough to federate server-side.
+type Federation struct {
+	repos      []*Repo
+	bestEffort bool
+}
+
+// NewFederation creates a Federation over the given repos.
+func NewFederation(repos ...*Repo) *Federation {
+	return &Federation{repos: repos}
+}
+
+// WithBestEffort makes Query collect partial results and
+// per-endpoint errors instead of failing outright when one member
+// endpoint errors.
+func (f *Federation) WithBestEffort() *Federation {
+	f.bestEffort = true
+	return f
+}
+
+// EndpointError records a Query failure from one member of a
+// Federation.
+type EndpointError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *EndpointError) Error() string { return fmt.Sprintf("%s: %s", e.Endpoint, e.Err) }
+func (e *EndpointError) Unwrap() error { return e.Err }
+
+// BestEffortError is returned by Federation.Query, alongside whatever
+// results did succeed, when WithBestEffort is set and at least one
+// member endpoint failed.
+type BestEffortError struct {
+	Errors []error
+}
+
+func (e *BestEffortError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "sparql: federated query had endpoint errors: " + strings.Join(msgs, "; ")
+}
+
+// Query dispatches q as a SELECT query to every member of the
+// federation concurrently, and merges the responses: the union of
+// Head.Vars, and the concatenation of all Results.Bindings. If any
+// member response is marked Distinct, the merged Bindings are
+// deduplicated.
+//
+// Without WithBestEffort, Query returns the first error encountered
+// and no results. With WithBestEffort, per-endpoint errors are
+// collected into a *BestEffortError returned alongside the results
+// merged from whichever endpoints did succeed.
+func (f *Federation) Query(ctx context.Context, q string) (*Results, error) {
+	type outcome struct {
+		endpoint string
+		res      *Results
+		err      error
+	}
+
+	outcomes := make([]outcome, len(f.repos))
+	var wg sync.WaitGroup
+	for i, repo := range f.repos {
+		wg.Add(1)
+		go func(i int, repo *Repo) {
+			defer wg.Done()
+			res, err := repo.QueryContext(ctx, q)
+			outcomes[i] = outcome{endpoint: repo.endpoint, res: res, err: err}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var merged Results
+	var errs []error
+	seenVar := make(map[string]bool)
+	distinct := false
+
+	for _, o := range outcomes {
+		if o.err != nil {
+			if !f.bestEffort {
+				return nil, o.err
+			}
+			errs = append(errs, &EndpointError{Endpoint: o.endpoint, Err: o.err})
+			continue
+		}
+
+		for _, v := range o.res.Head.Vars {
+			if !seenVar[v] {
+				seenVar[v] = true
+				merged.Head.Vars = append(merged.Head.Vars, v)
+			}
+		}
+		if o.res.Results.Distinct {
+			distinct = true
+		}
+		merged.Results.Bindings = append(merged.Results.Bindings, o.res.Results.Bindings...)
+	}
+
+	if distinct {
+		merged.Results.Distinct = true
+		merged.Results.Bindings = dedupBindings(merged.Results.Bindings)
+	}
+
+	if len(errs) > 0 {
+		return &merged, &BestEffortError{Errors: errs}
+	}
+	return &merged, nil
+}
+
+// dedupBindings removes solutions that are identical across all of
+// their bound variables.
+func dedupBindings(bindings []map[string]Binding) []map[string]Binding {
+	seen := make(map[string]bool, len(bindings))
+	out := make([]map[string]Binding, 0, len(bindings))
+	for _, b := range bindings {
+		key := bindingKey(b)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, b)
+	}
+	return out
+}
+
+// bindingKey renders a solution as a string uniquely identifying its
+// bound variables and values, for use as a deduplication key.
+func bindingKey(b map[string]Binding) string {
+	vars := make([]string, 0, len(b))
+	for v := range b {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+
+	var sb strings.Builder
+	for _, v := range vars {
+		val := b[v]
+		fmt.Fprintf(&sb, "%s=%s|%s|%s|%s;", v, val.Type, val.Value, val.Lang, val.DataType)
+	}
+	return sb.String()
+}
@@ -0,0 +1,237 @@
+package sparql
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/knakk/rdf"
+)
+
+// ResultFormat identifies a SPARQL result serialization that this
+// package knows how to request and parse, either a SPARQL Query
+// Results format (for Query/QueryStream) or an RDF graph
+// serialization (for Construct).
+type ResultFormat string
+
+// Query result formats.
+const (
+	FormatJSON ResultFormat = "application/sparql-results+json"
+	FormatXML  ResultFormat = "application/sparql-results+xml"
+	FormatCSV  ResultFormat = "text/csv"
+	FormatTSV  ResultFormat = "text/tab-separated-values"
+)
+
+// Graph (CONSTRUCT/DESCRIBE) result formats.
+//
+// JSON-LD is deliberately not offered here: the underlying
+// github.com/knakk/rdf has no JSON-LD codec, so advertising
+// application/ld+json in Accept would invite endpoints to reply in a
+// format Construct cannot parse.
+const (
+	FormatTurtle   ResultFormat = "text/turtle"
+	FormatNTriples ResultFormat = "application/n-triples"
+	FormatRDFXML   ResultFormat = "application/rdf+xml"
+)
+
+// WithAcceptFormats sets the result formats Query and Construct
+// advertise in the Accept header, in order of preference. The
+// response's Content-Type then selects which parser decodes the
+// body, so endpoints which ignore Accept and reply in any of the
+// listed formats are still handled correctly.
+func WithAcceptFormats(formats ...ResultFormat) func(*Repo) error {
+	return func(r *Repo) error {
+		r.acceptFormats = formats
+		return nil
+	}
+}
+
+// acceptHeader builds a q-valued Accept header from formats, listed
+// in order of preference (most preferred first, q=1.0).
+func acceptHeader(formats []ResultFormat) string {
+	if len(formats) == 0 {
+		return ""
+	}
+	parts := make([]string, len(formats))
+	step := 1.0 / float64(len(formats)+1)
+	q := 1.0
+	for i, f := range formats {
+		if i == 0 {
+			parts[i] = string(f)
+			continue
+		}
+		q -= step
+		parts[i] = fmt.Sprintf("%s;q=%.2f", f, q)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// mediaType returns the media type of a Content-Type header value,
+// stripped of any parameters (charset, boundary, etc).
+func mediaType(contentType string) ResultFormat {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return ResultFormat(mt)
+}
+
+// parseResults dispatches parsing of a SELECT/ASK response body based
+// on its Content-Type, defaulting to SPARQL Results JSON for unknown
+// or empty types, preserving prior behaviour.
+func parseResults(contentType string, body io.Reader) (*Results, error) {
+	switch mediaType(contentType) {
+	case FormatXML:
+		return parseXML(body)
+	case FormatCSV:
+		return parseDSV(body, ',')
+	case FormatTSV:
+		return parseDSV(body, '\t')
+	default:
+		return ParseJSON(body)
+	}
+}
+
+// parseGraph dispatches parsing of a CONSTRUCT/DESCRIBE response body
+// based on its Content-Type, defaulting to Turtle for unknown or
+// empty types, preserving prior behaviour.
+func parseGraph(contentType string, body io.Reader) ([]rdf.Triple, error) {
+	switch mediaType(contentType) {
+	case FormatNTriples:
+		return rdf.NewTripleDecoder(body, rdf.NTriples).DecodeAll()
+	case FormatRDFXML:
+		return rdf.NewTripleDecoder(body, rdf.RDFXML).DecodeAll()
+	default:
+		return rdf.NewTripleDecoder(body, rdf.Turtle).DecodeAll()
+	}
+}
+
+// xmlResults mirrors the structure of the SPARQL Query Results XML
+// Format (https://www.w3.org/TR/rdf-sparql-XMLres/).
+type xmlResults struct {
+	XMLName xml.Name `xml:"sparql"`
+	Head    struct {
+		Variables []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"variable"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+	} `xml:"head"`
+	Results struct {
+		Results []struct {
+			Bindings []struct {
+				Name    string  `xml:"name,attr"`
+				URI     *string `xml:"uri"`
+				BNode   *string `xml:"bnode"`
+				Literal *struct {
+					Value    string `xml:",chardata"`
+					Lang     string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+					Datatype string `xml:"datatype,attr"`
+				} `xml:"literal"`
+			} `xml:"binding"`
+		} `xml:"result"`
+	} `xml:"results"`
+}
+
+// parseXML parses an application/sparql-results+xml response into a
+// Results struct.
+func parseXML(r io.Reader) (*Results, error) {
+	var x xmlResults
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return nil, err
+	}
+
+	var res Results
+	for _, v := range x.Head.Variables {
+		res.Head.Vars = append(res.Head.Vars, v.Name)
+	}
+	for _, l := range x.Head.Links {
+		res.Head.Link = append(res.Head.Link, l.Href)
+	}
+
+	for _, xr := range x.Results.Results {
+		binding := make(map[string]Binding)
+		for _, b := range xr.Bindings {
+			switch {
+			case b.URI != nil:
+				binding[b.Name] = Binding{Type: "uri", Value: *b.URI}
+			case b.BNode != nil:
+				binding[b.Name] = Binding{Type: "bnode", Value: *b.BNode}
+			case b.Literal != nil:
+				bd := Binding{Value: b.Literal.Value}
+				switch {
+				case b.Literal.Datatype != "":
+					bd.Type = "typed-literal"
+					bd.DataType = b.Literal.Datatype
+				case b.Literal.Lang != "":
+					bd.Type = "literal"
+					bd.Lang = b.Literal.Lang
+				default:
+					bd.Type = "literal"
+				}
+				binding[b.Name] = bd
+			}
+		}
+		res.Results.Bindings = append(res.Results.Bindings, binding)
+	}
+
+	return &res, nil
+}
+
+// parseDSV parses a SPARQL 1.1 Query Results CSV or TSV response
+// (https://www.w3.org/TR/sparql11-results-csv-tsv/) into a Results
+// struct. Neither format carries a term's RDF type, so values are
+// necessarily classified by lossy heuristics: blank nodes by the
+// "_:" prefix, IRIs by a leading RFC 3986 scheme (covering non-slashed
+// forms like urn:, mailto:, tag: and doi:, not just http://), and
+// everything else as a plain literal. A literal that happens to start
+// with "word:" will be misclassified as an IRI; this is an inherent
+// limitation of the CSV/TSV result formats, not of this parser.
+func parseDSV(r io.Reader, comma rune) (*Results, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.LazyQuotes = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &Results{}, nil
+	}
+
+	var res Results
+	res.Head.Vars = rows[0]
+
+	for _, row := range rows[1:] {
+		binding := make(map[string]Binding)
+		for i, val := range row {
+			if i >= len(res.Head.Vars) || val == "" {
+				continue
+			}
+			binding[res.Head.Vars[i]] = bindingFromDSVValue(val)
+		}
+		res.Results.Bindings = append(res.Results.Bindings, binding)
+	}
+
+	return &res, nil
+}
+
+// schemeRe matches a leading RFC 3986 scheme ("alpha *( alpha | digit
+// | "+" | "-" | "." ) ':'"), e.g. "http:", "urn:", "mailto:", "doi:".
+var schemeRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+func bindingFromDSVValue(v string) Binding {
+	switch {
+	case strings.HasPrefix(v, "_:"):
+		return Binding{Type: "bnode", Value: strings.TrimPrefix(v, "_:")}
+	case schemeRe.MatchString(v):
+		return Binding{Type: "uri", Value: v}
+	default:
+		return Binding{Type: "literal", Value: v}
+	}
+}
@@ -0,0 +1,86 @@
+package sparql
+
+import "github.com/knakk/rdf"
+
+// Graph is an in-memory RDF graph built from a CONSTRUCT or DESCRIBE
+// query result. Unlike the []map[string]Binding helpers, it works in
+// terms of rdf.Term/rdf.Triple directly, so callers get proper IRI,
+// literal, language-tag and datatype handling without assuming any
+// particular variable naming.
+type Graph struct {
+	triples []rdf.Triple
+}
+
+// NewGraph builds a Graph from triples, e.g. the result of
+// Repo.Construct.
+func NewGraph(triples []rdf.Triple) *Graph {
+	return &Graph{triples: triples}
+}
+
+// Triples returns every triple in the graph.
+func (g *Graph) Triples() []rdf.Triple {
+	return g.triples
+}
+
+// SubjectsOf returns the distinct subjects of every triple with the
+// given predicate.
+func (g *Graph) SubjectsOf(pred rdf.IRI) []rdf.Term {
+	var out []rdf.Term
+	seen := make(map[string]bool)
+	for _, t := range g.triples {
+		if t.Pred.String() != pred.String() {
+			continue
+		}
+		if k := t.Subj.String(); !seen[k] {
+			seen[k] = true
+			out = append(out, t.Subj)
+		}
+	}
+	return out
+}
+
+// ObjectsOf returns the objects of every triple matching the given
+// subject and predicate.
+func (g *Graph) ObjectsOf(subj rdf.Term, pred rdf.IRI) []rdf.Term {
+	var out []rdf.Term
+	for _, t := range g.triples {
+		if t.Subj.String() == subj.String() && t.Pred.String() == pred.String() {
+			out = append(out, t.Obj)
+		}
+	}
+	return out
+}
+
+// Table is a typed view over a SELECT query's solutions, built from
+// *Results.
+type Table struct {
+	vars      []string
+	solutions []map[string]rdf.Term
+}
+
+// NewTable builds a Table from res, e.g. the result of Repo.Query.
+func NewTable(res *Results) *Table {
+	return &Table{vars: res.Head.Vars, solutions: res.Solutions()}
+}
+
+// Vars returns the query's selected variable names, in order.
+func (t *Table) Vars() []string {
+	return t.vars
+}
+
+// Column returns the bound value of name from every solution that
+// binds it, in solution order.
+func (t *Table) Column(name string) []rdf.Term {
+	var out []rdf.Term
+	for _, s := range t.solutions {
+		if v, ok := s[name]; ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Rows returns every solution as a map of variable name to bound term.
+func (t *Table) Rows() []map[string]rdf.Term {
+	return t.solutions
+}
@@ -0,0 +1,106 @@
+package sparql
+
+import (
+	"errors"
+	"net/url"
+)
+
+// errUpdateMethodGET is returned by UpdateMethod when called with GET,
+// since SPARQL Update operations are not idempotent.
+var errUpdateMethodGET = errors.New("sparql: GET is not a valid update method")
+
+// Method selects how a SPARQL query or update is transmitted to the
+// endpoint, per the SPARQL 1.1 Protocol
+// (https://www.w3.org/TR/sparql11-protocol/).
+type Method int
+
+const (
+	// GET sends the query as URL-encoded parameters in a GET request.
+	// It is the default for Query, since queries are idempotent and
+	// the response can be cached by intermediaries. If the resulting
+	// URL would exceed the repo's MaxGETLength, the request
+	// automatically falls back to POSTForm.
+	GET Method = iota
+	// POSTForm sends the query/update as an
+	// application/x-www-form-urlencoded POST body.
+	POSTForm
+	// POSTDirect sends the raw query/update string as the POST body,
+	// with Content-Type application/sparql-query (application/sparql-update
+	// for updates). Triple stores that expose a dedicated query or
+	// update endpoint (Fuseki, GraphDB, Virtuoso) commonly require this.
+	POSTDirect
+)
+
+// defaultMaxGETLength is the default URL length threshold above which
+// Query falls back from GET to POSTForm.
+const defaultMaxGETLength = 2048
+
+// QueryMethod sets how Query transmits requests to the endpoint.
+// It defaults to GET.
+func QueryMethod(m Method) func(*Repo) error {
+	return func(r *Repo) error {
+		r.queryMethod = m
+		return nil
+	}
+}
+
+// UpdateMethod sets how Update transmits requests to the endpoint.
+// It defaults to POSTForm, since SPARQL Update operations are not
+// idempotent and must not be sent as GET.
+func UpdateMethod(m Method) func(*Repo) error {
+	return func(r *Repo) error {
+		if m == GET {
+			return errUpdateMethodGET
+		}
+		r.updateMethod = m
+		return nil
+	}
+}
+
+// UpdateEndpoint sets a separate endpoint for Update operations. If
+// not set, Update requests are sent to the same endpoint as Query.
+func UpdateEndpoint(addr string) func(*Repo) error {
+	return func(r *Repo) error {
+		r.updateEndpoint = addr
+		return nil
+	}
+}
+
+// MaxGETLength sets the URL length threshold above which Query falls
+// back from GET to POSTForm. It has no effect unless the query method
+// is GET.
+func MaxGETLength(n int) func(*Repo) error {
+	return func(r *Repo) error {
+		r.maxGETLength = n
+		return nil
+	}
+}
+
+// DefaultGraphURI sets the default-graph-uri parameter(s) sent with
+// Query requests, as specified by the SPARQL 1.1 Protocol.
+func DefaultGraphURI(uris []string) func(*Repo) error {
+	return func(r *Repo) error {
+		r.defaultGraphURI = uris
+		return nil
+	}
+}
+
+// NamedGraphURI sets the named-graph-uri parameter(s) sent with Query
+// requests, as specified by the SPARQL 1.1 Protocol.
+func NamedGraphURI(uris []string) func(*Repo) error {
+	return func(r *Repo) error {
+		r.namedGraphURI = uris
+		return nil
+	}
+}
+
+// graphURIParams appends the repo's configured default/named graph
+// URIs to values, using the SPARQL 1.1 Protocol parameter names.
+func (r *Repo) graphURIParams(values url.Values) {
+	for _, g := range r.defaultGraphURI {
+		values.Add("default-graph-uri", g)
+	}
+	for _, g := range r.namedGraphURI {
+		values.Add("named-graph-uri", g)
+	}
+}
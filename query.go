@@ -0,0 +1,137 @@
+package sparql
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/knakk/rdf"
+)
+
+var xsdDateTime rdf.IRI
+
+func init() {
+	xsdDateTime, _ = rdf.NewIRI("http://www.w3.org/2001/XMLSchema#dateTime")
+}
+
+// Query is a parameterized SPARQL query template, analogous to a
+// prepared statement in database/sql. Placeholders in the template
+// use Go template syntax, e.g. {{.person}}, and are substituted with
+// values bound via Bind, rendered as properly escaped SPARQL syntax.
+// This avoids SPARQL injection when values come from user input.
+type Query struct {
+	tmpl *template.Template
+	data map[string]string
+	err  error
+}
+
+// NewQuery parses tmpl as a query template. The returned Query is
+// ready to have its placeholders filled in with Bind.
+func NewQuery(tmpl string) *Query {
+	t, err := template.New("sparql").Parse(tmpl)
+	return &Query{tmpl: t, data: make(map[string]string), err: err}
+}
+
+// Bind sets the value substituted for the named placeholder. value
+// can be a string, an int or float, a bool, a time.Time, or anything
+// implementing rdf.Term (rdf.IRI, rdf.Literal, rdf.Blank); each is
+// rendered with correct SPARQL syntax. Bind returns q, so calls can
+// be chained.
+func (q *Query) Bind(name string, value interface{}) *Query {
+	if q.err != nil {
+		return q
+	}
+	rendered, err := bindValue(value)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.data[name] = rendered
+	return q
+}
+
+// String renders the query with all bound values substituted, or
+// returns the first error encountered while parsing the template or
+// rendering a bound value.
+func (q *Query) String() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	var buf bytes.Buffer
+	if err := q.tmpl.Execute(&buf, q.data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// QueryPrepared renders q and executes it as a Query against the Repo.
+func (r *Repo) QueryPrepared(q *Query) (*Results, error) {
+	rendered, err := q.String()
+	if err != nil {
+		return nil, err
+	}
+	return r.Query(rendered)
+}
+
+// bindValue renders a Go value as a SPARQL term.
+func bindValue(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case rdf.Term:
+		// String() returns the bare lexical value (no angle brackets,
+		// quoting/escaping or "_:" prefix); Serialize renders the full
+		// term syntax, which is also valid SPARQL term syntax.
+		return t.Serialize(rdf.NTriples), nil
+	case string:
+		return `"` + escapeString(t) + `"`, nil
+	case int:
+		return strconv.Itoa(t), nil
+	case int32:
+		return strconv.FormatInt(int64(t), 10), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(t), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case time.Time:
+		return `"` + escapeString(t.Format(DateFormat)) + `"^^` + xsdDateTime.Serialize(rdf.NTriples), nil
+	default:
+		return "", fmt.Errorf("sparql: cannot bind value of type %T", v)
+	}
+}
+
+// escapeString escapes s per the SPARQL grammar's ECHAR production,
+// so it is safe to embed in a SPARQL string literal.
+func escapeString(s string) string {
+	if !strings.ContainsAny(s, "\\\"\n\r\t\b\f") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package sparql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knakk/rdf"
+)
+
+func TestEscapeString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"backslash", `back\slash`, `back\\slash`},
+		{"quote", `quo"te`, `quo\"te`},
+		{"newline", "line\nbreak", `line\nbreak`},
+		{"tab", "tab\ttab", `tab\ttab`},
+		{"carriage return", "cr\rcr", `cr\rcr`},
+		{"injection attempt", `"} ; DROP ALL ; {"`, `\"} ; DROP ALL ; {\"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeString(tt.in); got != tt.want {
+				t.Errorf("escapeString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindValue(t *testing.T) {
+	iri, err := rdf.NewIRI("http://example.org/Person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lit := rdf.NewTypedLiteral(`"} ; DROP ALL ; {"`, xsdString)
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{"string", "hello", `"hello"`},
+		{"injection attempt string", `"; DROP ALL ; "`, `"\"; DROP ALL ; \""`},
+		{"int", 42, "42"},
+		{"bool", true, "true"},
+		{"iri", iri, "<http://example.org/Person>"},
+		// xsd:string is RDF 1.1's implicit literal datatype, so
+		// Serialize(NTriples) omits the explicit ^^<...> suffix for it.
+		{"literal with injection payload", lit, `"\"} ; DROP ALL ; {\""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bindValue(tt.in)
+			if err != nil {
+				t.Fatalf("bindValue(%v) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("bindValue(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindValueTime(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	got, err := bindValue(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"2024-01-02T03:04:05Z"^^<http://www.w3.org/2001/XMLSchema#dateTime>`
+	if got != want {
+		t.Errorf("bindValue(time.Time) = %q, want %q", got, want)
+	}
+}
+
+func TestBindValueUnsupportedType(t *testing.T) {
+	if _, err := bindValue(struct{}{}); err == nil {
+		t.Error("bindValue(struct{}{}) = nil error, want error for unsupported type")
+	}
+}
@@ -2,6 +2,7 @@ package sparql
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,6 +28,18 @@ var ctvalue = "application/x-www-form-urlencoded"
 type Repo struct {
 	endpoint string
 	client   *http.Client
+
+	queryMethod    Method
+	updateMethod   Method
+	updateEndpoint string
+	maxGETLength   int
+
+	defaultGraphURI []string
+	namedGraphURI   []string
+
+	acceptFormats []ResultFormat
+
+	retry *retryPolicy
 }
 
 type header struct {
@@ -127,8 +140,11 @@ func termFromJSON(b Binding) (rdf.Term, error) {
 // of the repository.
 func NewRepo(addr string, options ...func(*Repo) error) (*Repo, error) {
 	r := Repo{
-		endpoint: addr,
-		client:   http.DefaultClient,
+		endpoint:     addr,
+		client:       http.DefaultClient,
+		queryMethod:  GET,
+		updateMethod: POSTForm,
+		maxGETLength: defaultMaxGETLength,
 	}
 	return &r, r.SetOption(options...)
 }
@@ -160,132 +176,275 @@ func Timeout(t time.Duration) func(*Repo) error {
 }
 
 // Query performs a SPARQL HTTP request to the Repo, and returns the
-// parsed application/sparql-results+json response.
+// parsed response, decoded according to its Content-Type. By default
+// the endpoint is asked for (and expected to return)
+// application/sparql-results+json; use WithAcceptFormats to negotiate
+// SPARQL Results XML or CSV/TSV instead.
+//
+// The request is sent using the Repo's configured query method
+// (GET by default, per the SPARQL 1.1 Protocol), falling back to
+// POSTForm if a GET request would exceed MaxGETLength.
+//
+// Query is equivalent to QueryContext with context.Background().
 func (r *Repo) Query(q string) (*Results, error) {
-	form := url.Values{}
-	form.Set("query", q)
-	b := form.Encode()
+	return r.QueryContext(context.Background(), q)
+}
 
-	// TODO make optional GET or Post, Query() should default GET (idempotent, cacheable)
-	// maybe new for updates: func (r *Repo) Update(q string) using POST?
-	req, err := http.NewRequest(
-		"POST",
-		r.endpoint,
-		bytes.NewBufferString(b))
+// QueryContext is like Query but carries a context, allowing the
+// caller to cancel or time out an in-flight request independently of
+// the Repo's http.Client timeout.
+func (r *Repo) QueryContext(ctx context.Context, q string) (*Results, error) {
+	accept := acceptHeader(r.acceptFormats)
+	if accept == "" {
+		accept = string(FormatJSON)
+	}
+
+	resp, err := r.doQuery(ctx, "Query", q, accept)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set(ct, ctvalue)
-	req.Header.Set(cl, strconv.Itoa(len(b)))
-	req.Header.Set("Accept", "application/sparql-results+json")
+	return parseResults(resp.Header.Get(ct), resp.Body)
+}
+
+// Update performs a SPARQL 1.1 Update HTTP request to the Repo. It is
+// sent to the Repo's UpdateEndpoint if one is configured, otherwise to
+// the same endpoint as Query.
+//
+// Update is equivalent to UpdateContext with context.Background().
+func (r *Repo) Update(q string) (string, error) {
+	return r.UpdateContext(context.Background(), q)
+}
+
+// UpdateContext is like Update but carries a context, allowing the
+// caller to cancel or time out an in-flight request independently of
+// the Repo's http.Client timeout.
+func (r *Repo) UpdateContext(ctx context.Context, q string) (string, error) {
+	if r.retry == nil || !r.retry.retryUpdates {
+		return r.updateOnce(ctx, q)
+	}
+
+	var result string
+	err := retryLoop(ctx, r.retry, func() error {
+		var attemptErr error
+		result, attemptErr = r.updateOnce(ctx, q)
+		return attemptErr
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// updateOnce performs a single Update attempt, with no retries.
+func (r *Repo) updateOnce(ctx context.Context, q string) (string, error) {
+	req, err := r.newUpdateRequest(ctx, q)
+	if err != nil {
+		return "", err
+	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, err := io.ReadAll(resp.Body)
-		var msg string
-		if err != nil {
-			msg = readFailMsg
-		} else {
-			if strings.TrimSpace(string(b)) != "" {
-				msg = rb + string(b)
-			}
-		}
-		return nil, fmt.Errorf("Query: SPARQL request failed: %s. "+msg, resp.Status)
+		return "", requestError("Update", resp)
+	}
+
+	return "OK", nil
+}
+
+// Construct performs a SPARQL HTTP request to the Repo, and returns
+// the result triples, decoded according to the response's
+// Content-Type. By default the endpoint is asked for (and expected to
+// return) Turtle; use WithAcceptFormats to negotiate N-Triples or
+// RDF/XML instead. JSON-LD responses are not supported.
+//
+// Construct is equivalent to ConstructContext with context.Background().
+func (r *Repo) Construct(q string) ([]rdf.Triple, error) {
+	return r.ConstructContext(context.Background(), q)
+}
+
+// ConstructContext is like Construct but carries a context, allowing
+// the caller to cancel or time out an in-flight request independently
+// of the Repo's http.Client timeout.
+func (r *Repo) ConstructContext(ctx context.Context, q string) ([]rdf.Triple, error) {
+	accept := acceptHeader(r.acceptFormats)
+	if accept == "" {
+		accept = string(FormatTurtle)
 	}
-	results, err := ParseJSON(resp.Body)
+
+	resp, err := r.doQuery(ctx, "Construct", q, accept)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	return results, nil
+	return parseGraph(resp.Header.Get(ct), resp.Body)
 }
 
-func (r *Repo) Update(q string) (string, error) {
-	form := url.Values{}
-	form.Set("update", q)
-	b := form.Encode()
+// doQuery sends q to the Repo's query endpoint using the configured
+// query method, and returns the response body for the caller to
+// decode and close. op names the calling method, for error messages.
+func (r *Repo) doQuery(ctx context.Context, op, q, accept string) (*http.Response, error) {
+	if r.retry == nil {
+		return r.doQueryOnce(ctx, op, q, accept)
+	}
 
-	// TODO make optional GET or Post, Query() should default GET (idempotent, cacheable)
-	// maybe new for updates: func (r *Repo) Update(q string) using POST?
-	req, err := http.NewRequest(
-		"POST",
-		r.endpoint,
-		bytes.NewBufferString(b))
+	var resp *http.Response
+	err := retryLoop(ctx, r.retry, func() error {
+		var attemptErr error
+		resp, attemptErr = r.doQueryOnce(ctx, op, q, accept)
+		return attemptErr
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return resp, nil
+}
 
-	req.Header.Set(ct, ctvalue)
-	req.Header.Set(cl, strconv.Itoa(len(b)))
-	req.Header.Set("Accept", "application/sparql-results+json")
+// doQueryOnce performs a single Query/Construct attempt, with no retries.
+func (r *Repo) doQueryOnce(ctx context.Context, op, q, accept string) (*http.Response, error) {
+	req, err := r.newQueryRequest(ctx, q, accept)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, err := io.ReadAll(resp.Body)
-		var msg string
-		if err != nil {
-			msg = readFailMsg
-		} else {
-			if strings.TrimSpace(string(b)) != "" {
-				msg = rb + string(b)
+		defer resp.Body.Close()
+		return nil, requestError(op, resp)
+	}
+
+	return resp, nil
+}
+
+// newQueryRequest builds the HTTP request for a Query or Construct
+// call, honouring the Repo's query method, graph URIs and GET/POST
+// fallback threshold, per the SPARQL 1.1 Protocol.
+func (r *Repo) newQueryRequest(ctx context.Context, q, accept string) (*http.Request, error) {
+	method := r.queryMethod
+
+	values := url.Values{}
+	values.Set("query", q)
+	r.graphURIParams(values)
+
+	if method == GET {
+		if u := r.endpoint + "?" + values.Encode(); len(u) <= r.maxGETLength {
+			req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+			if err != nil {
+				return nil, err
 			}
+			req.Header.Set("Accept", accept)
+			return req, nil
 		}
-		return "", fmt.Errorf("Query: SPARQL request failed: %s. "+msg, resp.Status)
+		// The encoded URL is too long for GET; fall back to POSTForm.
+		method = POSTForm
 	}
-	result := "OK"
 
-	return result, nil
+	if method == POSTDirect {
+		req, err := http.NewRequestWithContext(ctx, "POST", r.endpoint+graphURIQueryString(r), bytes.NewBufferString(q))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(ct, "application/sparql-query")
+		req.Header.Set(cl, strconv.Itoa(len(q)))
+		req.Header.Set("Accept", accept)
+		return req, nil
+	}
+
+	b := values.Encode()
+	req, err := http.NewRequestWithContext(ctx, "POST", r.endpoint, bytes.NewBufferString(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(ct, ctvalue)
+	req.Header.Set(cl, strconv.Itoa(len(b)))
+	req.Header.Set("Accept", accept)
+	return req, nil
 }
 
-// Construct performs a SPARQL HTTP request to the Repo, and returns the
-// result triples.
-func (r *Repo) Construct(q string) ([]rdf.Triple, error) {
+// newUpdateRequest builds the HTTP request for an Update call,
+// honouring the Repo's update method and UpdateEndpoint.
+func (r *Repo) newUpdateRequest(ctx context.Context, q string) (*http.Request, error) {
+	endpoint := r.endpoint
+	if r.updateEndpoint != "" {
+		endpoint = r.updateEndpoint
+	}
+
+	if r.updateMethod == POSTDirect {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBufferString(q))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(ct, "application/sparql-update")
+		req.Header.Set(cl, strconv.Itoa(len(q)))
+		req.Header.Set("Accept", "application/sparql-results+json")
+		return req, nil
+	}
+
 	form := url.Values{}
-	form.Set("query", q)
-	form.Set("format", "text/turtle")
+	form.Set("update", q)
 	b := form.Encode()
 
-	req, err := http.NewRequest(
-		"POST",
-		r.endpoint,
-		bytes.NewBufferString(b))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBufferString(b))
 	if err != nil {
 		return nil, err
 	}
-
 	req.Header.Set(ct, ctvalue)
 	req.Header.Set(cl, strconv.Itoa(len(b)))
-	req.Header.Set("Accept", "text/turtle")
+	req.Header.Set("Accept", "application/sparql-results+json")
+	return req, nil
+}
 
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, err
+// graphURIQueryString renders the Repo's default/named graph URIs as a
+// URL query string, suitable for appending to a POSTDirect request's URL.
+func graphURIQueryString(r *Repo) string {
+	if len(r.defaultGraphURI) == 0 && len(r.namedGraphURI) == 0 {
+		return ""
 	}
-	defer resp.Body.Close()
+	values := url.Values{}
+	r.graphURIParams(values)
+	return "?" + values.Encode()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		b, err := io.ReadAll(resp.Body)
-		var msg string
-		if err != nil {
-			msg = readFailMsg
-		} else {
-			if strings.TrimSpace(string(b)) != "" {
-				msg = rb + string(b)
-			}
-		}
-		return nil, fmt.Errorf("Construct: SPARQL request failed: %s. "+msg, resp.Status)
+// requestFailure is returned by requestError. Its fields let the
+// retry policy decide whether the failure is transient, without
+// parsing the error message.
+type requestFailure struct {
+	op         string
+	statusCode int
+	status     string
+	retryAfter string
+	msg        string
+}
+
+func (e *requestFailure) Error() string {
+	return fmt.Sprintf("%s: SPARQL request failed: %s. %s", e.op, e.status, e.msg)
+}
+
+// requestError reads resp's body (if any) and formats a SPARQL
+// request failure, in the style used throughout this package.
+func requestError(op string, resp *http.Response) error {
+	b, err := io.ReadAll(resp.Body)
+	var msg string
+	if err != nil {
+		msg = readFailMsg
+	} else if strings.TrimSpace(string(b)) != "" {
+		msg = rb + string(b)
+	}
+	return &requestFailure{
+		op:         op,
+		statusCode: resp.StatusCode,
+		status:     resp.Status,
+		retryAfter: resp.Header.Get("Retry-After"),
+		msg:        msg,
 	}
-	dec := rdf.NewTripleDecoder(resp.Body, rdf.Turtle)
-	return dec.DecodeAll()
 }
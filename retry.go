@@ -0,0 +1,185 @@
+package sparql
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy configures how Query, Construct and (optionally)
+// Update retry transient failures.
+type retryPolicy struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	retryUpdates    bool
+}
+
+// WithRetry configures Query and Construct to retry transient
+// failures — network errors and HTTP 429/500/502/503/504 — up to
+// maxAttempts total attempts, using exponential backoff with full
+// jitter (each attempt waits a random duration between 0 and the
+// backoff, which doubles every attempt and is capped at maxInterval).
+// A Retry-After response header, if present, overrides the computed
+// backoff.
+//
+// Update is not retried unless RetryUpdates is also set, since
+// SPARQL Update operations are not guaranteed idempotent.
+func WithRetry(maxAttempts int, initialInterval, maxInterval time.Duration) func(*Repo) error {
+	return func(r *Repo) error {
+		if maxAttempts < 1 {
+			return errors.New("sparql: maxAttempts must be at least 1")
+		}
+		if r.retry == nil {
+			r.retry = &retryPolicy{}
+		}
+		r.retry.maxAttempts = maxAttempts
+		r.retry.initialInterval = initialInterval
+		r.retry.maxInterval = maxInterval
+		return nil
+	}
+}
+
+// RetryUpdates opts Update in to the Repo's retry policy. It has no
+// effect unless WithRetry is also set.
+func RetryUpdates(allow bool) func(*Repo) error {
+	return func(r *Repo) error {
+		if r.retry == nil {
+			r.retry = &retryPolicy{}
+		}
+		r.retry.retryUpdates = allow
+		return nil
+	}
+}
+
+// retryLoop calls attempt up to policy.maxAttempts times. attempt is
+// responsible for stashing any successful result in the caller's
+// scope; retryLoop only inspects the error it returns. It sleeps
+// between attempts per policy's backoff (or the failure's Retry-After
+// header, if present), and gives up early if ctx is done or the
+// error isn't retryable.
+func retryLoop(ctx context.Context, policy *retryPolicy, attempt func() error) error {
+	maxAttempts := policy.maxAttempts
+	if maxAttempts < 1 {
+		// RetryUpdates without WithRetry leaves maxAttempts unset:
+		// make a single attempt, as if no policy were configured.
+		maxAttempts = 1
+	}
+	var lastErr error
+	for n := 1; n <= maxAttempts; n++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if n == maxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		wait := policy.backoff(n)
+		if ra, ok := retryAfterFromErr(err); ok {
+			wait = ra
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// backoff returns the jittered delay to wait after the given attempt
+// (1-indexed) fails: full jitter over an exponential backoff that
+// doubles each attempt starting at initialInterval, capped at
+// maxInterval.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.initialInterval
+	for i := 1; i < attempt; i++ {
+		if d >= p.maxInterval {
+			d = p.maxInterval
+			break
+		}
+		d *= 2
+	}
+	if d > p.maxInterval {
+		d = p.maxInterval
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// isRetryable reports whether err represents a transient failure
+// worth retrying: a retryable HTTP status, or a transport-level error
+// other than context cancellation/deadline.
+func isRetryable(err error) bool {
+	var rf *requestFailure
+	if errors.As(err, &rf) {
+		return isRetryableStatus(rf.statusCode)
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterFromErr extracts and parses the Retry-After header
+// recorded on a requestFailure, if any.
+func retryAfterFromErr(err error) (time.Duration, bool) {
+	var rf *requestFailure
+	if !errors.As(err, &rf) {
+		return 0, false
+	}
+	return parseRetryAfter(rf.retryAfter)
+}
+
+// parseRetryAfter parses a Retry-After header value in either the
+// delta-seconds or HTTP-date form (RFC 7231 section 7.1.3).
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d, returning early with ctx's error if it is done
+// first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -20,6 +20,12 @@ func init() {
 	xsdString, _ = rdf.NewIRI("http://www.w3.org/2001/XMLSchema#string")
 }
 
+// FindObjectValueByPredicate returns the first solution whose "p"
+// binding contains needle.
+//
+// Deprecated: assumes a fixed "p" variable name and operates on raw
+// strings rather than typed RDF terms. Use Graph.ObjectsOf (for
+// CONSTRUCT/DESCRIBE results) or Table (for SELECT results) instead.
 func FindObjectValueByPredicate(needle string, haystack []map[string]Binding) map[string]Binding {
 	for _, term := range haystack {
 		// Check if the predicate (p) matches the needle
@@ -34,6 +40,11 @@ func FindObjectValueByPredicate(needle string, haystack []map[string]Binding) ma
 	return map[string]Binding{}
 }
 
+// GetValue returns the value bound to needle in the first solution
+// of haystack.
+//
+// Deprecated: operates on raw strings rather than typed RDF terms.
+// Use Table.Column instead.
 func GetValue(needle string, haystack []map[string]Binding) string {
 	if len(haystack) == 0 {
 		return ""
@@ -50,6 +61,12 @@ func GetValue(needle string, haystack []map[string]Binding) string {
 	return ""
 }
 
+// FindObjectValueBySpecifiedPredicate returns the first solution
+// whose predicate binding contains needle.
+//
+// Deprecated: operates on raw strings rather than typed RDF terms.
+// Use Graph.ObjectsOf (for CONSTRUCT/DESCRIBE results) or Table (for
+// SELECT results) instead.
 func FindObjectValueBySpecifiedPredicate(needle string, predicate string, haystack []map[string]Binding) map[string]Binding {
 	for _, term := range haystack {
 		// Check if the predicate (p) matches the needle
@@ -64,6 +81,11 @@ func FindObjectValueBySpecifiedPredicate(needle string, predicate string, haysta
 	return map[string]Binding{}
 }
 
+// ListOfSubjects groups solutions by their "s" binding.
+//
+// Deprecated: assumes a fixed "s" variable name and operates on raw
+// strings rather than typed RDF terms. Use Graph.SubjectsOf (for
+// CONSTRUCT/DESCRIBE results) or Table (for SELECT results) instead.
 func ListOfSubjects(results []map[string]Binding) map[string][]map[string]Binding {
 	var toReturn = make(map[string][]map[string]Binding)
 
@@ -82,6 +104,10 @@ func ListOfSubjects(results []map[string]Binding) map[string][]map[string]Bindin
 	return toReturn
 }
 
+// ListOf groups solutions by their needle binding.
+//
+// Deprecated: operates on raw strings rather than typed RDF terms.
+// Use Table instead.
 func ListOf(results []map[string]Binding, needle string) map[string][]map[string]Binding {
 	var toReturn = make(map[string][]map[string]Binding)
 
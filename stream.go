@@ -0,0 +1,157 @@
+package sparql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/knakk/rdf"
+)
+
+// ResultStream iterates over the solutions of a SPARQL SELECT query
+// one at a time, without materializing the full result set in memory.
+// It must be closed after use.
+type ResultStream struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+	head header
+
+	cur  map[string]Binding
+	err  error
+	done bool
+}
+
+// QueryStream performs a SPARQL HTTP request to the Repo like Query,
+// but returns a ResultStream that decodes the results.bindings array
+// element-by-element as the caller advances it, keeping the HTTP
+// response body open instead of buffering every solution up front.
+// This is intended for result sets too large to hold in memory.
+//
+// Streaming is only supported for the SPARQL Results JSON format, so
+// QueryStream always requests it, regardless of the Repo's configured
+// acceptFormats.
+func (r *Repo) QueryStream(q string) (*ResultStream, error) {
+	resp, err := r.doQuery(context.Background(), "QueryStream", q, string(FormatJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	if mt := mediaType(resp.Header.Get(ct)); mt != "" && mt != FormatJSON {
+		resp.Body.Close()
+		return nil, fmt.Errorf("QueryStream: streaming is only supported for %s, got %s", FormatJSON, mt)
+	}
+
+	s := &ResultStream{body: resp.Body, dec: json.NewDecoder(resp.Body)}
+	if err := s.init(); err != nil {
+		s.body.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// init walks the JSON response up to, but not including, the first
+// element of results.bindings, recording head.vars and head.link
+// along the way.
+func (s *ResultStream) init() error {
+	if _, err := s.dec.Token(); err != nil { // opening '{'
+		return err
+	}
+	for s.dec.More() {
+		t, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		switch key {
+		case "head":
+			if err := s.dec.Decode(&s.head); err != nil {
+				return err
+			}
+		case "results":
+			return s.enterBindings()
+		default:
+			var discard json.RawMessage
+			if err := s.dec.Decode(&discard); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.New(`sparql: malformed SPARQL JSON results: missing "results"`)
+}
+
+// enterBindings walks into the results object and consumes the
+// opening '[' of its bindings array, leaving the decoder positioned
+// at the first binding (if any).
+func (s *ResultStream) enterBindings() error {
+	if _, err := s.dec.Token(); err != nil { // opening '{' of "results"
+		return err
+	}
+	for s.dec.More() {
+		t, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		if key == "bindings" {
+			_, err := s.dec.Token() // opening '[' of "bindings"
+			return err
+		}
+		var discard json.RawMessage
+		if err := s.dec.Decode(&discard); err != nil {
+			return err
+		}
+	}
+	return errors.New(`sparql: malformed SPARQL JSON results: missing "bindings"`)
+}
+
+// Next advances the stream to the next solution, and reports whether
+// one is available. It must be called before the first call to Scan.
+func (s *ResultStream) Next() bool {
+	if s.done || s.err != nil {
+		return false
+	}
+	s.cur = nil
+	if !s.dec.More() {
+		s.done = true
+		return false
+	}
+	var b map[string]Binding
+	if err := s.dec.Decode(&b); err != nil {
+		s.err = err
+		s.done = true
+		return false
+	}
+	s.cur = b
+	return true
+}
+
+// Scan returns the current solution as a map of bound variables to
+// RDF terms.
+func (s *ResultStream) Scan() (map[string]rdf.Term, error) {
+	if s.cur == nil {
+		return nil, errors.New("sparql: Scan called before Next or after end of results")
+	}
+	solution := make(map[string]rdf.Term, len(s.cur))
+	for k, v := range s.cur {
+		t, err := termFromJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		solution[k] = t
+	}
+	return solution, nil
+}
+
+// Err returns the first error encountered while advancing the
+// stream, if any.
+func (s *ResultStream) Err() error {
+	return s.err
+}
+
+// Close releases the underlying HTTP response body. It must be
+// called once the caller is done with the stream.
+func (s *ResultStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,77 @@
+package sparql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/knakk/rdf"
+)
+
+const cannedStreamBody = `{
+  "head": {"vars": ["s", "p", "o"]},
+  "results": {
+    "bindings": [
+      {"s": {"type": "uri", "value": "http://example.org/a"}, "p": {"type": "uri", "value": "http://example.org/knows"}, "o": {"type": "uri", "value": "http://example.org/b"}},
+      {"s": {"type": "uri", "value": "http://example.org/a"}, "p": {"type": "uri", "value": "http://example.org/name"}, "o": {"type": "literal", "value": "Alice"}}
+    ]
+  }
+}`
+
+func TestQueryStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/sparql-results+json")
+		_, _ = w.Write([]byte(cannedStreamBody))
+	}))
+	defer srv.Close()
+
+	repo, err := NewRepo(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := repo.QueryStream("SELECT * WHERE { ?s ?p ?o }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var solutions []map[string]rdf.Term
+	for stream.Next() {
+		sol, err := stream.Scan()
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		solutions = append(solutions, sol)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream ended with error: %v", err)
+	}
+
+	if len(solutions) != 2 {
+		t.Fatalf("got %d solutions, want 2", len(solutions))
+	}
+	if got := solutions[0]["o"].String(); got != "http://example.org/b" {
+		t.Errorf("solutions[0][o] = %q, want %q", got, "http://example.org/b")
+	}
+	if got := solutions[1]["o"].String(); got != "Alice" {
+		t.Errorf("solutions[1][o] = %q, want %q", got, "Alice")
+	}
+}
+
+func TestQueryStreamRejectsNonJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("s,p,o\n"))
+	}))
+	defer srv.Close()
+
+	repo, err := NewRepo(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.QueryStream("SELECT * WHERE { ?s ?p ?o }"); err == nil {
+		t.Error("QueryStream with non-JSON Content-Type = nil error, want error")
+	}
+}